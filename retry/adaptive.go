@@ -0,0 +1,87 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package retry
+
+import "time"
+
+// Adaptive is a Strategy whose Timer paces itself according to the
+// observed outcome of each attempt, rather than following a fixed
+// schedule: its sleep duration grows multiplicatively on failure
+// and decays multiplicatively on success. This suits clients that
+// must self-tune to the pressure a backend is willing to tolerate,
+// such as a client of a rate-limited API.
+//
+// Callers must report each attempt's outcome, for example via
+// Attempt.Succeeded and Attempt.Failed, for the strategy to adapt;
+// without that feedback the sleep duration never changes.
+type Adaptive struct {
+	// MinSleep is the smallest sleep duration Adaptive will ever
+	// use.
+	MinSleep time.Duration
+
+	// MaxSleep is the largest sleep duration Adaptive will ever
+	// use. A zero value means no maximum.
+	MaxSleep time.Duration
+
+	// AttackConstant determines how quickly the sleep duration is
+	// reduced following a success: sleep -= sleep >> AttackConstant.
+	AttackConstant uint
+
+	// DecayConstant determines how quickly the sleep duration
+	// grows following a failure: sleep += sleep >> DecayConstant.
+	DecayConstant uint
+}
+
+// defaultMinSleep is used as the initial sleep duration, and as a
+// floor against getting stuck at zero, when a caller leaves MinSleep
+// unset.
+const defaultMinSleep = time.Millisecond
+
+// NewTimer implements Strategy.NewTimer.
+func (a Adaptive) NewTimer(now time.Time) Timer {
+	sleep := a.MinSleep
+	if sleep <= 0 {
+		sleep = defaultMinSleep
+	}
+	return &adaptiveTimer{
+		strategy: a,
+		sleep:    sleep,
+	}
+}
+
+type adaptiveTimer struct {
+	strategy Adaptive
+	sleep    time.Duration
+}
+
+// NextSleep implements Timer.NextSleep. It never terminates the
+// attempt; termination should be layered on top with LimitCount or
+// LimitTime if needed.
+func (t *adaptiveTimer) NextSleep(now time.Time) (time.Duration, bool) {
+	return t.sleep, true
+}
+
+// Outcome implements Outcomer.Outcome, adapting the sleep duration
+// according to whether the attempt succeeded.
+func (t *adaptiveTimer) Outcome(success bool) {
+	if success {
+		t.sleep -= t.sleep >> t.strategy.AttackConstant
+		if t.sleep < t.strategy.MinSleep {
+			t.sleep = t.strategy.MinSleep
+		}
+		if t.sleep <= 0 {
+			// A zero MinSleep, combined with a small enough
+			// AttackConstant, can drive sleep to exactly zero, from
+			// which it could never grow again (sleep >> n is always
+			// zero). Seed a nonzero floor so a later failure can
+			// still back off.
+			t.sleep = defaultMinSleep
+		}
+		return
+	}
+	t.sleep += t.sleep >> t.strategy.DecayConstant
+	if t.strategy.MaxSleep > 0 && t.sleep > t.strategy.MaxSleep {
+		t.sleep = t.strategy.MaxSleep
+	}
+}
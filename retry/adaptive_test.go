@@ -0,0 +1,125 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package retry_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/utils/retry"
+)
+
+type adaptiveSuite struct{}
+
+var _ = gc.Suite(&adaptiveSuite{})
+
+func (*adaptiveSuite) TestGrowsOnFailureAndDecaysOnSuccess(c *gc.C) {
+	// MinSleep is left unset: with it set, the timer starts at
+	// exactly MinSleep, and the decay/attack sequence below always
+	// lands below that floor, exercising the clamp that
+	// TestRespectsMinAndMaxSleep already covers rather than the
+	// raw growth/decay arithmetic this test is about.
+	strategy := retry.Adaptive{
+		MaxSleep:       time.Second,
+		AttackConstant: 1,
+		DecayConstant:  1,
+	}
+	timer := strategy.NewTimer(time.Now())
+
+	d, ok := timer.NextSleep(time.Now())
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(d, gc.Equals, time.Millisecond)
+
+	outcomer, ok := timer.(retry.Outcomer)
+	c.Assert(ok, gc.Equals, true)
+
+	outcomer.Outcome(false)
+	d, _ = timer.NextSleep(time.Now())
+	c.Assert(d, gc.Equals, 3*time.Millisecond/2)
+
+	outcomer.Outcome(true)
+	d, _ = timer.NextSleep(time.Now())
+	c.Assert(d, gc.Equals, 3*time.Millisecond/4)
+}
+
+func (*adaptiveSuite) TestRespectsMinAndMaxSleep(c *gc.C) {
+	strategy := retry.Adaptive{
+		MinSleep:       10 * time.Millisecond,
+		MaxSleep:       20 * time.Millisecond,
+		AttackConstant: 0,
+		DecayConstant:  0,
+	}
+	timer := strategy.NewTimer(time.Now())
+	outcomer := timer.(retry.Outcomer)
+
+	// A success with AttackConstant 0 would drop below MinSleep,
+	// so it should be clamped.
+	outcomer.Outcome(true)
+	d, _ := timer.NextSleep(time.Now())
+	c.Assert(d, gc.Equals, 10*time.Millisecond)
+
+	// Repeated failures should be clamped at MaxSleep.
+	for i := 0; i < 5; i++ {
+		outcomer.Outcome(false)
+	}
+	d, _ = timer.NextSleep(time.Now())
+	c.Assert(d, gc.Equals, 20*time.Millisecond)
+}
+
+func (*adaptiveSuite) TestRecoversFromZeroSleepWithUnsetMinSleep(c *gc.C) {
+	strategy := retry.Adaptive{
+		AttackConstant: 0,
+		DecayConstant:  1,
+	}
+	timer := strategy.NewTimer(time.Now())
+	outcomer := timer.(retry.Outcomer)
+
+	// AttackConstant 0 drives sleep straight to zero; without a
+	// floor it would get stuck there forever.
+	outcomer.Outcome(true)
+	d, _ := timer.NextSleep(time.Now())
+	c.Assert(d > 0, gc.Equals, true)
+
+	// A subsequent failure must still be able to grow the sleep.
+	outcomer.Outcome(false)
+	grown, _ := timer.NextSleep(time.Now())
+	c.Assert(grown > d, gc.Equals, true)
+}
+
+// capturingStrategy wraps a Strategy and records the Timer it
+// creates, so a test can inspect the Timer's state directly instead
+// of only observing Attempt's boolean return values.
+type capturingStrategy struct {
+	strategy retry.Strategy
+	timer    retry.Timer
+}
+
+func (s *capturingStrategy) NewTimer(now time.Time) retry.Timer {
+	s.timer = s.strategy.NewTimer(now)
+	return s.timer
+}
+
+func (*adaptiveSuite) TestAttemptSucceededAndFailedDriveOutcome(c *gc.C) {
+	strategy := &capturingStrategy{strategy: retry.Adaptive{
+		MinSleep:       time.Millisecond,
+		MaxSleep:       time.Second,
+		AttackConstant: 1,
+		DecayConstant:  1,
+	}}
+	a := retry.Start(strategy, nil, nil)
+	c.Assert(a.Next(), gc.Equals, true)
+
+	before, _ := strategy.timer.NextSleep(time.Now())
+
+	a.Failed()
+	afterFailed, _ := strategy.timer.NextSleep(time.Now())
+	c.Assert(afterFailed > before, gc.Equals, true)
+
+	a.Succeeded()
+	afterSucceeded, _ := strategy.timer.NextSleep(time.Now())
+	c.Assert(afterSucceeded < afterFailed, gc.Equals, true)
+
+	c.Assert(a.HasNext(), gc.Equals, true)
+}
@@ -0,0 +1,117 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package retry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juju/utils/clock"
+)
+
+// CallArgs holds the parameters for a call to Call.
+type CallArgs struct {
+	// Func is the function that will be retried if it returns
+	// an error.
+	Func func() error
+
+	// IsFatalError, if not nil, is called with any error returned
+	// by Func. If it returns true, the error is considered fatal
+	// and Call returns immediately without retrying.
+	IsFatalError func(err error) bool
+
+	// NotifyFunc, if not nil, is called after each failed attempt,
+	// before waiting to retry. It is passed the error returned by
+	// Func and the attempt number, starting at 1.
+	NotifyFunc func(lastError error, attempt int)
+
+	// Strategy determines how many times Func will be retried and
+	// how long will be slept between attempts.
+	Strategy Strategy
+
+	// Clock is used to implement the Strategy's timing; if it is
+	// nil, clock.WallClock will be used.
+	Clock clock.Clock
+
+	// Stop, if not nil, is used to abort the retry loop early; see
+	// Start for details. Use Context instead to abort via a
+	// context.Context.
+	Stop <-chan struct{}
+
+	// Context, if not nil, is used to abort the retry loop early;
+	// see StartContext for details. At most one of Context and
+	// Stop should be set.
+	Context context.Context
+}
+
+// RetryStopped is returned by Call when Stop is closed, or Context
+// is cancelled, while retrying.
+type RetryStopped struct {
+	// LastError holds the error returned by the last call to
+	// args.Func.
+	LastError error
+}
+
+func (e *RetryStopped) Error() string {
+	return fmt.Sprintf("retry stopped: %v", e.LastError)
+}
+
+// AttemptsExceeded is returned by Call when the given strategy's
+// attempts are all used up.
+type AttemptsExceeded struct {
+	// LastError holds the error returned by the last call to
+	// args.Func.
+	LastError error
+
+	// Attempts holds the number of attempts that were made.
+	Attempts int
+}
+
+func (e *AttemptsExceeded) Error() string {
+	return fmt.Sprintf("attempt count exceeded: %v", e.LastError)
+}
+
+// Call calls args.Func until it succeeds, args.IsFatalError
+// determines that it has failed for good, or the retry strategy
+// given by args.Strategy is exhausted.
+//
+// If args.Func returns no error, Call returns nil.
+// If args.IsFatalError returns true for the error returned by
+// args.Func, that error is returned unchanged.
+// If the retries are stopped via args.Stop or args.Context, Call
+// returns a *RetryStopped error holding the last error seen.
+// Otherwise, once the strategy is exhausted, Call returns an
+// *AttemptsExceeded error holding the last error seen and the
+// number of attempts made.
+func Call(args CallArgs) error {
+	var a *Attempt
+	if args.Context != nil {
+		a = StartContext(args.Context, args.Strategy, args.Clock)
+	} else {
+		a = Start(args.Strategy, args.Clock, args.Stop)
+	}
+	var lastErr error
+	for a.Next() {
+		lastErr = args.Func()
+		if lastErr == nil {
+			return nil
+		}
+		if args.IsFatalError != nil && args.IsFatalError(lastErr) {
+			return lastErr
+		}
+		if args.NotifyFunc != nil {
+			args.NotifyFunc(lastErr, a.Count())
+		}
+		if !a.HasNext() {
+			break
+		}
+	}
+	if a.Err() != nil {
+		return &RetryStopped{LastError: lastErr}
+	}
+	return &AttemptsExceeded{
+		LastError: lastErr,
+		Attempts:  a.Count(),
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/utils/retry"
+)
+
+type callSuite struct{}
+
+var _ = gc.Suite(&callSuite{})
+
+// limitedStrategy sleeps for d between attempts and allows at most
+// count attempts before stopping. The first attempt made by an
+// Attempt is always free (see Attempt.Next), so the timer itself
+// only needs to approve count-1 further attempts.
+type limitedStrategy struct {
+	d     time.Duration
+	count int
+}
+
+func (s limitedStrategy) NewTimer(now time.Time) retry.Timer {
+	return &limitedTimer{d: s.d, remaining: s.count - 1}
+}
+
+// limitedTimer sleeps for d and allows itself to be consulted
+// remaining times before stopping.
+type limitedTimer struct {
+	d         time.Duration
+	remaining int
+}
+
+func (t *limitedTimer) NextSleep(now time.Time) (time.Duration, bool) {
+	if t.remaining <= 0 {
+		return 0, false
+	}
+	t.remaining--
+	return t.d, true
+}
+
+func (*callSuite) TestCallSucceedsFirstTime(c *gc.C) {
+	calls := 0
+	err := retry.Call(retry.CallArgs{
+		Func: func() error {
+			calls++
+			return nil
+		},
+		Strategy: limitedStrategy{count: 3},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(calls, gc.Equals, 1)
+}
+
+func (*callSuite) TestCallRetriesUntilSuccess(c *gc.C) {
+	calls := 0
+	err := retry.Call(retry.CallArgs{
+		Func: func() error {
+			calls++
+			if calls < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		},
+		Strategy: limitedStrategy{count: 5},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(calls, gc.Equals, 3)
+}
+
+func (*callSuite) TestCallReturnsFatalErrorUnchanged(c *gc.C) {
+	fatal := errors.New("fatal")
+	err := retry.Call(retry.CallArgs{
+		Func: func() error {
+			return fatal
+		},
+		IsFatalError: func(err error) bool {
+			return err == fatal
+		},
+		Strategy: limitedStrategy{count: 5},
+	})
+	c.Assert(err, gc.Equals, fatal)
+}
+
+func (*callSuite) TestCallExhaustsAttempts(c *gc.C) {
+	failure := errors.New("persistent failure")
+	var notified []int
+	err := retry.Call(retry.CallArgs{
+		Func: func() error {
+			return failure
+		},
+		NotifyFunc: func(lastErr error, attempt int) {
+			c.Check(lastErr, gc.Equals, failure)
+			notified = append(notified, attempt)
+		},
+		Strategy: limitedStrategy{count: 3},
+	})
+	exceeded, ok := err.(*retry.AttemptsExceeded)
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(exceeded.LastError, gc.Equals, failure)
+	c.Assert(exceeded.Attempts, gc.Equals, 3)
+	c.Assert(notified, gc.DeepEquals, []int{1, 2, 3})
+}
+
+func (*callSuite) TestCallStopped(c *gc.C) {
+	stop := make(chan struct{})
+	close(stop)
+	failure := errors.New("failure")
+	err := retry.Call(retry.CallArgs{
+		Func: func() error {
+			return failure
+		},
+		Strategy: limitedStrategy{d: time.Second, count: 5},
+		Stop:     stop,
+	})
+	stopped, ok := err.(*retry.RetryStopped)
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(stopped.LastError, gc.Equals, failure)
+}
+
+func (*callSuite) TestCallCancelledContext(c *gc.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	failure := errors.New("failure")
+	err := retry.Call(retry.CallArgs{
+		Func: func() error {
+			return failure
+		},
+		Strategy: limitedStrategy{d: time.Second, count: 5},
+		Context:  ctx,
+	})
+	stopped, ok := err.(*retry.RetryStopped)
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(stopped.LastError, gc.Equals, failure)
+}
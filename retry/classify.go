@@ -0,0 +1,132 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package retry
+
+import (
+	"context"
+	"errors"
+)
+
+// Action is returned by a Classifier to say what a retry loop
+// should do next in response to an error.
+type Action int
+
+const (
+	// Retry indicates that the attempt should be retried.
+	Retry Action = iota
+
+	// Fail indicates that the error is fatal: the attempt has
+	// failed for good and should be returned to the caller
+	// unchanged.
+	Fail
+
+	// Succeed indicates that the error should be treated as if the
+	// attempt had in fact succeeded.
+	Succeed
+)
+
+// Classifier decides what a retry loop should do in response to an
+// error returned by the operation being retried.
+type Classifier interface {
+	// Classify returns the Action that should be taken in response
+	// to err, which is never nil.
+	Classify(err error) Action
+}
+
+// ClassifierFunc adapts a plain function to a Classifier.
+type ClassifierFunc func(err error) Action
+
+// Classify implements Classifier.Classify.
+func (f ClassifierFunc) Classify(err error) Action {
+	return f(err)
+}
+
+// Any returns a Classifier that consults each of classifiers in
+// turn and returns the first Action other than Retry; if every
+// classifier returns Retry, or no classifiers are given, Any
+// returns Retry.
+func Any(classifiers ...Classifier) Classifier {
+	return ClassifierFunc(func(err error) Action {
+		for _, cl := range classifiers {
+			if action := cl.Classify(err); action != Retry {
+				return action
+			}
+		}
+		return Retry
+	})
+}
+
+// TemporaryError classifies errors that implement the interface
+//
+//	interface{ Temporary() bool }
+//
+// as implemented by net.Error: Retry if Temporary returns true,
+// Fail otherwise.
+var TemporaryError Classifier = ClassifierFunc(func(err error) Action {
+	if terr, ok := err.(interface{ Temporary() bool }); ok && terr.Temporary() {
+		return Retry
+	}
+	return Fail
+})
+
+// ContextError classifies context.Canceled and
+// context.DeadlineExceeded as Fail, since retrying cannot help once
+// the caller's context has been cancelled or its deadline has
+// passed. Every other error is classified as Retry.
+var ContextError Classifier = ClassifierFunc(func(err error) Action {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return Fail
+	}
+	return Retry
+})
+
+// IsFatalError adapts classify into a function suitable for use as
+// CallArgs.IsFatalError. Since CallArgs has no way to report success
+// independently of Func's own return value, a Succeed classification
+// is treated the same as Retry here, not as Fail; use Attempt.ShouldRetry
+// directly if that distinction matters to the caller.
+func IsFatalError(classify Classifier) func(error) bool {
+	return func(err error) bool {
+		return classify.Classify(err) == Fail
+	}
+}
+
+// ShouldRetry records err as a's last error (retrievable with
+// LastError) and reports whether the attempt loop should retry.
+// It returns false if err is nil. Otherwise it consults classifiers
+// in turn, and the first one to return an Action other than Retry
+// decides: on Fail, it records err as the last error and returns
+// false; on Succeed, it clears the last error (as after a nil err)
+// and returns false, so the caller can't tell Succeed apart from a
+// genuine success by looking at LastError. If every classifier
+// returns Retry, or none is given, it records err and returns true.
+// It formalizes the
+// shouldRetry(err) helper that Attempt-based retry loops otherwise
+// tend to write by hand; see the package example for the pattern it
+// replaces.
+func (a *Attempt) ShouldRetry(err error, classifiers ...Classifier) bool {
+	if err == nil {
+		a.lastErr = nil
+		return false
+	}
+	for _, cl := range classifiers {
+		switch cl.Classify(err) {
+		case Fail:
+			a.lastErr = err
+			return false
+		case Succeed:
+			a.lastErr = nil
+			return false
+		}
+	}
+	a.lastErr = err
+	return true
+}
+
+// LastError returns the most recent error passed to a.ShouldRetry,
+// or nil if ShouldRetry has not yet been called, was last called
+// with a nil error, or last classified its error as Succeed.
+func LastError(a *Attempt) error {
+	return a.lastErr
+}
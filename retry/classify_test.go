@@ -0,0 +1,89 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/utils/retry"
+)
+
+type classifySuite struct{}
+
+var _ = gc.Suite(&classifySuite{})
+
+type temporaryError bool
+
+func (e temporaryError) Error() string   { return "temporary error" }
+func (e temporaryError) Temporary() bool { return bool(e) }
+
+func (*classifySuite) TestTemporaryError(c *gc.C) {
+	c.Assert(retry.TemporaryError.Classify(temporaryError(true)), gc.Equals, retry.Retry)
+	c.Assert(retry.TemporaryError.Classify(temporaryError(false)), gc.Equals, retry.Fail)
+	c.Assert(retry.TemporaryError.Classify(errors.New("boom")), gc.Equals, retry.Fail)
+}
+
+func (*classifySuite) TestContextError(c *gc.C) {
+	c.Assert(retry.ContextError.Classify(context.Canceled), gc.Equals, retry.Fail)
+	c.Assert(retry.ContextError.Classify(context.DeadlineExceeded), gc.Equals, retry.Fail)
+	c.Assert(retry.ContextError.Classify(errors.New("boom")), gc.Equals, retry.Retry)
+}
+
+func (*classifySuite) TestContextErrorUnwrapsWrappedErrors(c *gc.C) {
+	wrapped := fmt.Errorf("doing the thing: %w", context.Canceled)
+	c.Assert(retry.ContextError.Classify(wrapped), gc.Equals, retry.Fail)
+
+	wrapped = fmt.Errorf("doing the thing: %w", context.DeadlineExceeded)
+	c.Assert(retry.ContextError.Classify(wrapped), gc.Equals, retry.Fail)
+}
+
+func (*classifySuite) TestAnyStopsAtFirstNonRetry(c *gc.C) {
+	any := retry.Any(retry.ContextError, retry.TemporaryError)
+	c.Assert(any.Classify(context.Canceled), gc.Equals, retry.Fail)
+	c.Assert(any.Classify(temporaryError(true)), gc.Equals, retry.Retry)
+	c.Assert(any.Classify(errors.New("boom")), gc.Equals, retry.Fail)
+}
+
+func (*classifySuite) TestAnyWithNoClassifiersRetries(c *gc.C) {
+	c.Assert(retry.Any().Classify(errors.New("boom")), gc.Equals, retry.Retry)
+}
+
+func (*classifySuite) TestIsFatalError(c *gc.C) {
+	isFatal := retry.IsFatalError(retry.ContextError)
+	c.Assert(isFatal(context.Canceled), gc.Equals, true)
+	c.Assert(isFatal(errors.New("boom")), gc.Equals, false)
+}
+
+func (*classifySuite) TestShouldRetryAndLastError(c *gc.C) {
+	a := retry.Start(limitedStrategy{count: 3}, nil, nil)
+	c.Assert(a.Next(), gc.Equals, true)
+
+	boom := errors.New("boom")
+	c.Assert(a.ShouldRetry(boom), gc.Equals, true)
+	c.Assert(retry.LastError(a), gc.Equals, boom)
+
+	c.Assert(a.ShouldRetry(nil), gc.Equals, false)
+	c.Assert(retry.LastError(a), gc.IsNil)
+}
+
+func (*classifySuite) TestShouldRetryWithClassifier(c *gc.C) {
+	a := retry.Start(limitedStrategy{count: 3}, nil, nil)
+	c.Assert(a.Next(), gc.Equals, true)
+	c.Assert(a.ShouldRetry(context.Canceled, retry.ContextError), gc.Equals, false)
+	c.Assert(retry.LastError(a), gc.Equals, context.Canceled)
+}
+
+func (*classifySuite) TestShouldRetryWithSucceedClassifier(c *gc.C) {
+	alreadyDone := retry.ClassifierFunc(func(err error) retry.Action {
+		return retry.Succeed
+	})
+	a := retry.Start(limitedStrategy{count: 3}, nil, nil)
+	c.Assert(a.Next(), gc.Equals, true)
+	c.Assert(a.ShouldRetry(errors.New("not really an error"), alreadyDone), gc.Equals, false)
+	c.Assert(retry.LastError(a), gc.IsNil)
+}
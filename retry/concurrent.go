@@ -0,0 +1,104 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package retry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/utils/clock"
+)
+
+// ConcurrentRetrier throttles a pool of goroutines that share a
+// single, possibly failing, backend. Each goroutine calls Throttle
+// before attempting an operation, and reports the outcome with
+// Failed or Succeeded afterwards. When one goroutine reports a
+// failure, every goroutine's next call to Throttle blocks until the
+// backoff given by the retrier's Strategy has elapsed; a subsequent
+// call to Succeeded resets the backoff.
+type ConcurrentRetrier struct {
+	strategy Strategy
+	clock    clock.Clock
+
+	mu      sync.Mutex
+	timer   Timer
+	until   time.Time
+	pending int
+}
+
+// NewConcurrentRetrier returns a ConcurrentRetrier that uses
+// strategy to determine how long to hold off new attempts after a
+// failure is reported. If clk is nil, clock.WallClock is used.
+func NewConcurrentRetrier(strategy Strategy, clk clock.Clock) *ConcurrentRetrier {
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	return &ConcurrentRetrier{
+		strategy: strategy,
+		clock:    clk,
+	}
+}
+
+// Throttle blocks until it is reasonable to make another attempt
+// against the backend. It returns immediately unless a failure has
+// previously been reported and the resulting backoff has not yet
+// elapsed.
+func (r *ConcurrentRetrier) Throttle() {
+	for {
+		r.mu.Lock()
+		until := r.until
+		if until.IsZero() || !r.clock.Now().Before(until) {
+			r.mu.Unlock()
+			return
+		}
+		r.pending++
+		r.mu.Unlock()
+
+		<-r.clock.After(until.Sub(r.clock.Now()))
+
+		r.mu.Lock()
+		r.pending--
+		r.mu.Unlock()
+	}
+}
+
+// Failed reports that an attempt against the backend has failed.
+// It starts (or advances) the retrier's Strategy timer, causing
+// every subsequent call to Throttle to block until the resulting
+// sleep has elapsed. If the timer reports that no more attempts
+// should be made, the backoff is cleared instead, so Throttle no
+// longer blocks, and the next call to Failed starts a new timer.
+func (r *ConcurrentRetrier) Failed() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.clock.Now()
+	if r.timer == nil {
+		r.timer = r.strategy.NewTimer(now)
+	}
+	sleep, ok := r.timer.NextSleep(now)
+	if !ok {
+		r.timer = nil
+		r.until = time.Time{}
+		return
+	}
+	r.until = now.Add(sleep)
+}
+
+// Succeeded reports that an attempt against the backend has
+// succeeded, resetting the backoff started by any prior call to
+// Failed.
+func (r *ConcurrentRetrier) Succeeded() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timer = nil
+	r.until = time.Time{}
+}
+
+// PendingCount returns the number of goroutines currently blocked
+// in Throttle, for observability.
+func (r *ConcurrentRetrier) PendingCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pending
+}
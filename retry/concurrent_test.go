@@ -0,0 +1,84 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package retry_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/utils/retry"
+)
+
+type concurrentSuite struct{}
+
+var _ = gc.Suite(&concurrentSuite{})
+
+func (*concurrentSuite) TestThrottleWithoutFailureDoesNotBlock(c *gc.C) {
+	clk := testing.NewClock(time.Now())
+	r := retry.NewConcurrentRetrier(limitedStrategy{d: time.Minute, count: 1000}, clk)
+	done := make(chan struct{})
+	go func() {
+		r.Throttle()
+		close(done)
+	}()
+	assertReceive(c, done, "unthrottled Throttle call")
+}
+
+func (*concurrentSuite) TestFailedBlocksThrottleUntilSleepElapses(c *gc.C) {
+	clk := testing.NewClock(time.Now())
+	r := retry.NewConcurrentRetrier(limitedStrategy{d: time.Minute, count: 1000}, clk)
+	r.Failed()
+
+	done := make(chan struct{})
+	go func() {
+		r.Throttle()
+		close(done)
+	}()
+
+	// Wait deterministically until the goroutine has found the
+	// backoff still running and registered itself as pending in
+	// Throttle, rather than guessing how long that takes.
+	assertPendingCount(c, r, 1)
+
+	select {
+	case <-done:
+		c.Fatalf("Throttle returned before the backoff elapsed")
+	default:
+	}
+
+	clk.Advance(time.Minute)
+	assertReceive(c, done, "Throttle call after backoff elapsed")
+	c.Assert(r.PendingCount(), gc.Equals, 0)
+}
+
+// assertPendingCount polls r.PendingCount() until it equals want,
+// failing the test if that doesn't happen within a reasonable time.
+func assertPendingCount(c *gc.C, r *retry.ConcurrentRetrier, want int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if r.PendingCount() == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			c.Fatalf("timed out waiting for PendingCount to reach %d", want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (*concurrentSuite) TestSucceededResetsBackoff(c *gc.C) {
+	clk := testing.NewClock(time.Now())
+	r := retry.NewConcurrentRetrier(limitedStrategy{d: time.Minute, count: 1000}, clk)
+	r.Failed()
+	r.Succeeded()
+
+	done := make(chan struct{})
+	go func() {
+		r.Throttle()
+		close(done)
+	}()
+	assertReceive(c, done, "Throttle call after Succeeded reset the backoff")
+}
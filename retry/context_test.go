@@ -0,0 +1,58 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package retry_test
+
+import (
+	"context"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/utils/retry"
+)
+
+type contextSuite struct{}
+
+var _ = gc.Suite(&contextSuite{})
+
+func (*contextSuite) TestStartContextCancelled(c *gc.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a := retry.StartContext(ctx, limitedStrategy{d: time.Second, count: 5}, nil)
+	c.Assert(a.Next(), gc.Equals, true)
+	c.Assert(a.Next(), gc.Equals, false)
+	c.Assert(a.Err(), gc.Equals, context.Canceled)
+}
+
+func (*contextSuite) TestStartContextDeadlineExceeded(c *gc.C) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	a := retry.StartContext(ctx, limitedStrategy{d: time.Hour, count: 5}, nil)
+	c.Assert(a.Next(), gc.Equals, true)
+	c.Assert(a.Next(), gc.Equals, false)
+	c.Assert(a.Err(), gc.Equals, context.DeadlineExceeded)
+}
+
+func (*contextSuite) TestStartWithStopSetsErrStopped(c *gc.C) {
+	stop := make(chan struct{})
+	close(stop)
+	a := retry.Start(limitedStrategy{d: time.Second, count: 5}, nil, stop)
+	c.Assert(a.Next(), gc.Equals, true)
+	c.Assert(a.Next(), gc.Equals, false)
+	c.Assert(a.Err(), gc.Equals, retry.ErrStopped)
+}
+
+func (*contextSuite) TestErrNilWhenAttemptsExhausted(c *gc.C) {
+	a := retry.Start(limitedStrategy{count: 1}, nil, nil)
+	c.Assert(a.Next(), gc.Equals, true)
+	c.Assert(a.Next(), gc.Equals, false)
+	c.Assert(a.Err(), gc.IsNil)
+}
+
+func (*contextSuite) TestStartContextErrNilWhenAttemptsExhausted(c *gc.C) {
+	a := retry.StartContext(context.Background(), limitedStrategy{count: 1}, nil)
+	c.Assert(a.Next(), gc.Equals, true)
+	c.Assert(a.Next(), gc.Equals, false)
+	c.Assert(a.Err(), gc.IsNil)
+}
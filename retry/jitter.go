@@ -0,0 +1,111 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package retry
+
+import (
+	"math/rand"
+	"time"
+)
+
+// JitterMode determines the algorithm used by Jitter to randomize
+// the sleep durations returned by the Strategy it wraps.
+type JitterMode int
+
+const (
+	// FullJitter chooses a sleep duration uniformly distributed
+	// in the range [0, d], where d is the duration that would
+	// otherwise have been used.
+	FullJitter JitterMode = iota
+
+	// EqualJitter keeps half of the underlying duration and
+	// randomizes the rest, choosing a sleep duration in the
+	// range [d/2, d/2+d/2].
+	EqualJitter
+
+	// Decorrelated chooses a sleep duration in the range
+	// [base, max(base, prev*3)], capped at the duration that
+	// would otherwise have been used, where prev is the duration
+	// chosen on the previous call (zero on the first call) and
+	// base is the smallest duration seen so far from the wrapped
+	// Strategy.
+	//
+	// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/.
+	Decorrelated
+)
+
+// Jitter returns a Strategy that wraps s, randomizing the sleep
+// duration it returns on each attempt according to mode. This
+// avoids many concurrent clients of a Strategy such as Exponential
+// synchronizing their retries against a shared backend.
+//
+// If r is nil, a new source seeded from the current time is used
+// for each Attempt; pass a non-nil *rand.Rand to get deterministic
+// behaviour, for example in tests.
+func Jitter(s Strategy, mode JitterMode, r *rand.Rand) Strategy {
+	return &jitterStrategy{
+		strategy: s,
+		mode:     mode,
+		rand:     r,
+	}
+}
+
+type jitterStrategy struct {
+	strategy Strategy
+	mode     JitterMode
+	rand     *rand.Rand
+}
+
+// NewTimer implements Strategy.NewTimer.
+func (j *jitterStrategy) NewTimer(now time.Time) Timer {
+	r := j.rand
+	if r == nil {
+		r = rand.New(rand.NewSource(now.UnixNano()))
+	}
+	return &jitterTimer{
+		timer: j.strategy.NewTimer(now),
+		mode:  j.mode,
+		rand:  r,
+	}
+}
+
+type jitterTimer struct {
+	timer Timer
+	mode  JitterMode
+	rand  *rand.Rand
+
+	haveMin bool
+	min     time.Duration
+	prev    time.Duration
+}
+
+// NextSleep implements Timer.NextSleep.
+func (t *jitterTimer) NextSleep(now time.Time) (time.Duration, bool) {
+	d, ok := t.timer.NextSleep(now)
+	if !ok || d <= 0 {
+		return d, ok
+	}
+	switch t.mode {
+	case FullJitter:
+		d = time.Duration(t.rand.Int63n(int64(d) + 1))
+	case EqualJitter:
+		half := d / 2
+		d = half + time.Duration(t.rand.Int63n(int64(half)+1))
+	case Decorrelated:
+		if !t.haveMin || d < t.min {
+			t.min = d
+			t.haveMin = true
+		}
+		max := t.prev * 3
+		if max < t.min {
+			max = t.min
+		}
+		sleep := t.min + time.Duration(t.rand.Int63n(int64(max-t.min)+1))
+		if sleep > d {
+			sleep = d
+		}
+		t.prev = sleep
+		d = sleep
+	}
+	return d, true
+}
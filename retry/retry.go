@@ -15,11 +15,18 @@
 package retry
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"github.com/juju/utils/clock"
 )
 
+// ErrStopped is returned by Attempt.Err when an attempt was
+// terminated because a value was received on its stop channel,
+// rather than because its context (if any) was cancelled.
+var ErrStopped = errors.New("retry stopped")
+
 // Strategy is implemented by types that represent a retry strategy.
 type Strategy interface {
 	NewTimer(now time.Time) Timer
@@ -38,32 +45,71 @@ type Timer interface {
 	NextSleep(now time.Time) (time.Duration, bool)
 }
 
+// Outcomer is implemented by Timer implementations, such as the one
+// returned by Adaptive, that adapt their sleep durations to the
+// observed outcome of each attempt. Attempt.Succeeded and
+// Attempt.Failed call Outcome on the current Strategy's Timer if it
+// implements this interface, and are no-ops otherwise.
+type Outcomer interface {
+	// Outcome reports whether the most recent attempt succeeded.
+	Outcome(success bool)
+}
+
 // Attempt represents a running retry attempt.
 type Attempt struct {
 	clock   clock.Clock
 	stop    <-chan struct{}
+	ctx     context.Context
 	timer   Timer
 	count   int
 	waited  bool
 	running bool
+	err     error
+	lastErr error
 }
 
 // Start begins a new sequence of attempts for the given strategy. If
 // clk is nil, clock.WallClock will be used. If a value is received on
-// stop while waiting, the attempt will be aborted.
+// stop while waiting, the attempt will be aborted and Err will return
+// ErrStopped.
+//
+// Start is a special case of StartContext that has no context and
+// aborts via stop instead.
 func Start(strategy Strategy, clk clock.Clock, stop <-chan struct{}) *Attempt {
+	return newAttempt(nil, stop, strategy, clk)
+}
+
+// StartContext begins a new sequence of attempts for the given
+// strategy. If clk is nil, clock.WallClock will be used. If ctx is
+// cancelled or its deadline expires while waiting between attempts,
+// the attempt is aborted and Err returns ctx.Err().
+func StartContext(ctx context.Context, strategy Strategy, clk clock.Clock) *Attempt {
+	return newAttempt(ctx, nil, strategy, clk)
+}
+
+func newAttempt(ctx context.Context, stop <-chan struct{}, strategy Strategy, clk clock.Clock) *Attempt {
 	if clk == nil {
 		clk = clock.WallClock
 	}
 	return &Attempt{
 		clock:   clk,
 		stop:    stop,
+		ctx:     ctx,
 		timer:   strategy.NewTimer(clk.Now()),
 		waited:  true,
 		running: true,
 	}
 }
 
+// Err returns the reason the attempt was terminated early: either
+// the error from the context passed to StartContext, or ErrStopped
+// if a value was received on the stop channel passed to Start. It
+// returns nil if the attempt is still running, or if it finished
+// because the strategy's timer simply had no more attempts to make.
+func (a *Attempt) Err() error {
+	return a.err
+}
+
 // Next waits until it is time to perform the next attempt or returns
 // false if it is time to stop trying.
 // It always returns true the first time it is called - we are guaranteed to
@@ -83,6 +129,26 @@ func (a *Attempt) Count() int {
 	return a.count
 }
 
+// Succeeded reports that the current attempt succeeded. If the
+// Strategy's Timer implements Outcomer, its Outcome method is
+// called with true.
+func (a *Attempt) Succeeded() {
+	a.outcome(true)
+}
+
+// Failed reports that the current attempt failed. If the Strategy's
+// Timer implements Outcomer, its Outcome method is called with
+// false.
+func (a *Attempt) Failed() {
+	a.outcome(false)
+}
+
+func (a *Attempt) outcome(success bool) {
+	if ot, ok := a.timer.(Outcomer); ok {
+		ot.Outcome(success)
+	}
+}
+
 // HasNext waits until it is time to perform the next attempt
 // and returns the value that Next will return.
 // Multiple consecutive calls to HasNext without
@@ -98,11 +164,18 @@ func (a *Attempt) HasNext() bool {
 		a.running = false
 		return false
 	}
-	a.waited = true
+	var ctxDone <-chan struct{}
+	if a.ctx != nil {
+		ctxDone = a.ctx.Done()
+	}
 	select {
 	case <-a.clock.After(sleep):
+	case <-ctxDone:
+		a.running = false
+		a.err = a.ctx.Err()
 	case <-a.stop:
 		a.running = false
+		a.err = ErrStopped
 	}
 	return a.running
 }